@@ -0,0 +1,75 @@
+package commands
+
+import (
+	"log"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+const (
+	benchCountFlag = "bench-count"
+
+	benchmarksConfigFile   = "benchmarks.yml"
+	defaultBenchMultiplier = 2.0
+	benchSignificanceAlpha = 0.05
+)
+
+// benchmarkRequirements configures how a problem's benchmarks are compared
+// against the baseline solution, analogous to coverageRequirements read via
+// getCoverageRequirements.
+type benchmarkRequirements struct {
+	Count      int
+	Multiplier float64
+	Benchmarks []string // empty means all benchmarks in the package are gated
+}
+
+// benchmarksConfig is the on-disk shape of a problem's benchmarks.yml.
+type benchmarksConfig struct {
+	Count      *int     `yaml:"count"`
+	Multiplier *float64 `yaml:"multiplier"`
+	Benchmarks []string `yaml:"benchmarks"`
+}
+
+// getBenchmarkRequirements reads <problemDir>/benchmarks.yml, if present, and
+// overlays it onto the defaults derived from --bench-count.
+func getBenchmarkRequirements(problemDir string, defaultCount int) benchmarkRequirements {
+	req := benchmarkRequirements{
+		Count:      defaultCount,
+		Multiplier: defaultBenchMultiplier,
+	}
+
+	raw, err := os.ReadFile(filepath.Join(problemDir, benchmarksConfigFile))
+	if os.IsNotExist(err) {
+		return req
+	}
+	if err != nil {
+		log.Fatalf("reading %s: %s", benchmarksConfigFile, err)
+	}
+
+	var cfg benchmarksConfig
+	if err := yaml.Unmarshal(raw, &cfg); err != nil {
+		log.Fatalf("parsing %s: %s", benchmarksConfigFile, err)
+	}
+
+	if cfg.Count != nil {
+		req.Count = *cfg.Count
+	}
+	if cfg.Multiplier != nil {
+		req.Multiplier = *cfg.Multiplier
+	}
+	req.Benchmarks = cfg.Benchmarks
+
+	return req
+}
+
+// benchRegexp builds the -test.bench argument gating only the configured
+// benchmarks, or every benchmark when none are listed.
+func (r benchmarkRequirements) benchRegexp() string {
+	if len(r.Benchmarks) == 0 {
+		return "."
+	}
+	return "^(" + strings.Join(r.Benchmarks, "|") + ")$"
+}
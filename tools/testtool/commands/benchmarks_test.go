@@ -0,0 +1,18 @@
+package commands
+
+import "testing"
+
+func TestBenchRegexpAllByDefault(t *testing.T) {
+	req := benchmarkRequirements{}
+	if got := req.benchRegexp(); got != "." {
+		t.Fatalf("benchRegexp() = %q, want %q", got, ".")
+	}
+}
+
+func TestBenchRegexpGatesListedBenchmarks(t *testing.T) {
+	req := benchmarkRequirements{Benchmarks: []string{"BenchmarkFoo", "BenchmarkBar"}}
+	want := "^(BenchmarkFoo|BenchmarkBar)$"
+	if got := req.benchRegexp(); got != want {
+		t.Fatalf("benchRegexp() = %q, want %q", got, want)
+	}
+}
@@ -0,0 +1,329 @@
+package commands
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+
+	"github.com/spf13/cobra"
+)
+
+const (
+	noCacheFlag  = "no-cache"
+	cacheDirFlag = "cache-dir"
+
+	cacheAppName = "shad-go"
+	cacheSubdir  = "check-task"
+
+	cacheStatusPass = "pass"
+	cacheStatusFail = "fail"
+)
+
+// cacheOptions controls whether testSubmission may read or write the
+// on-disk result cache.
+type cacheOptions struct {
+	Disabled bool
+	Dir      string
+}
+
+// cacheManifest is the persisted record of a single check-task run, keyed by
+// the content digest of everything that run depended on.
+type cacheManifest struct {
+	Status          string  `json:"status"`
+	LintOutput      string  `json:"lint_output"`
+	TestOutput      string  `json:"test_output"`
+	BenchstatTables string  `json:"benchstat_tables"`
+	CoveragePercent float64 `json:"coverage_percent"`
+}
+
+// resolveCacheDir returns the directory check-task results are cached under,
+// honouring --cache-dir and falling back to $XDG_CACHE_HOME (via
+// os.UserCacheDir, which already respects it on Linux).
+func resolveCacheDir(cmd *cobra.Command) (string, error) {
+	dir, err := cmd.Flags().GetString(cacheDirFlag)
+	if err != nil {
+		return "", err
+	}
+	if dir != "" {
+		return dir, nil
+	}
+
+	userCache, err := os.UserCacheDir()
+	if err != nil {
+		return "", fmt.Errorf("resolving default cache dir: %w", err)
+	}
+	return filepath.Join(userCache, cacheAppName, cacheSubdir), nil
+}
+
+func manifestDir(cacheDir, problem, digest string) string {
+	return filepath.Join(cacheDir, problem, digest)
+}
+
+func loadCacheManifest(cacheDir, problem, digest string) (*cacheManifest, bool) {
+	raw, err := os.ReadFile(filepath.Join(manifestDir(cacheDir, problem, digest), "manifest.json"))
+	if err != nil {
+		return nil, false
+	}
+
+	var m cacheManifest
+	if err := json.Unmarshal(raw, &m); err != nil {
+		return nil, false
+	}
+	return &m, true
+}
+
+func saveCacheManifest(cacheDir, problem, digest string, m *cacheManifest) error {
+	dir := manifestDir(cacheDir, problem, digest)
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return fmt.Errorf("creating cache dir: %w", err)
+	}
+
+	raw, err := json.MarshalIndent(m, "", "  ")
+	if err != nil {
+		return fmt.Errorf("marshaling cache manifest: %w", err)
+	}
+
+	return os.WriteFile(filepath.Join(dir, "manifest.json"), raw, 0644)
+}
+
+// captureOutput runs fn with os.Stdout and os.Stderr temporarily redirected
+// through pipes so their combined text can be stored in a cache manifest,
+// while still streaming through to the real terminal as before. The standard
+// log package is redirected the same way: its default logger captured the
+// original os.Stderr *os.File at init time, so reassigning the os.Stderr
+// variable alone doesn't touch where log.Printf writes.
+func captureOutput(fn func() error) (stdout, stderr string, err error) {
+	origStdout, origStderr := os.Stdout, os.Stderr
+	origLogOutput := log.Writer()
+
+	stdoutR, stdoutW, pipeErr := os.Pipe()
+	if pipeErr != nil {
+		return "", "", pipeErr
+	}
+	stderrR, stderrW, pipeErr := os.Pipe()
+	if pipeErr != nil {
+		return "", "", pipeErr
+	}
+
+	os.Stdout, os.Stderr = stdoutW, stderrW
+	log.SetOutput(stderrW)
+
+	var stdoutBuf, stderrBuf bytes.Buffer
+	done := make(chan struct{}, 2)
+	go func() { io.Copy(io.MultiWriter(origStdout, &stdoutBuf), stdoutR); done <- struct{}{} }()
+	go func() { io.Copy(io.MultiWriter(origStderr, &stderrBuf), stderrR); done <- struct{}{} }()
+
+	err = fn()
+
+	log.SetOutput(origLogOutput)
+	_ = stdoutW.Close()
+	_ = stderrW.Close()
+	<-done
+	<-done
+
+	os.Stdout, os.Stderr = origStdout, origStderr
+	return stdoutBuf.String(), stderrBuf.String(), err
+}
+
+// replayCacheManifest prints a previously cached run's output, so a cache hit
+// looks the same to the user as a fresh pass.
+func replayCacheManifest(m *cacheManifest) {
+	log.Printf("cache hit: replaying stored result")
+	if m.TestOutput != "" {
+		fmt.Fprint(os.Stdout, m.TestOutput)
+	}
+	if m.BenchstatTables != "" {
+		fmt.Fprint(os.Stderr, m.BenchstatTables)
+	}
+	if m.LintOutput != "" {
+		fmt.Fprint(os.Stdout, m.LintOutput)
+	}
+	if m.CoveragePercent > 0 {
+		log.Printf("coverage is %.2f%%", m.CoveragePercent)
+	}
+}
+
+// hashedFile is a single entry hashed into the submission digest.
+type hashedFile struct {
+	relPath string
+	mode    os.FileMode
+	sha256  [32]byte
+}
+
+// digestFiles collects (relative path, mode, sha256 of contents) for every
+// file under root, or for root itself when it is a single file; a missing
+// root yields nil. computeSubmissionDigest folds these entries into a single
+// digest, mirroring the way cmd/go invalidates cached test output when its
+// inputs change.
+func digestFiles(root string) ([]hashedFile, error) {
+	var files []hashedFile
+
+	info, err := os.Stat(root)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	if !info.IsDir() {
+		h, err := hashFile(root, info.Mode())
+		if err != nil {
+			return nil, err
+		}
+		return []hashedFile{{relPath: filepath.Base(root), mode: info.Mode(), sha256: h}}, nil
+	}
+
+	err = filepath.Walk(root, func(p string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if info.IsDir() {
+			return nil
+		}
+
+		relPath, err := filepath.Rel(root, p)
+		if err != nil {
+			return err
+		}
+
+		h, err := hashFile(p, info.Mode())
+		if err != nil {
+			return err
+		}
+
+		files = append(files, hashedFile{relPath: relPath, mode: info.Mode(), sha256: h})
+		return nil
+	})
+	return files, err
+}
+
+func hashFile(p string, mode os.FileMode) ([32]byte, error) {
+	f, err := os.Open(p)
+	if err != nil {
+		return [32]byte{}, err
+	}
+	defer func() { _ = f.Close() }()
+
+	h := sha256.New()
+	if _, err := io.Copy(h, f); err != nil {
+		return [32]byte{}, err
+	}
+
+	var sum [32]byte
+	copy(sum[:], h.Sum(nil))
+	return sum, nil
+}
+
+// computeSubmissionDigest fingerprints the student repo problem directory
+// plus the private files that get copied alongside it (tests, protected
+// files, testdata, go.mod, go.sum, .golangci.yml, and the helper modules when
+// the problem has its own go.mod) and the problem's benchmarks.yml. Student
+// repo files outside the problem directory and the baseline solution that
+// compareToBaseline benchmarks inside privateRepo are not hashed.
+func computeSubmissionDigest(studentRepo, privateRepo, problem string) (string, error) {
+	privateProblem := path.Join(privateRepo, problem)
+
+	var files []hashedFile
+
+	studentFiles, err := digestFiles(path.Join(studentRepo, problem))
+	if err != nil {
+		return "", fmt.Errorf("hashing student repo: %w", err)
+	}
+	files = append(files, prefixed("student/", studentFiles)...)
+
+	for _, name := range relPaths(privateRepo, listTestFiles(privateProblem)) {
+		f, err := digestFiles(path.Join(privateRepo, name))
+		if err != nil {
+			return "", fmt.Errorf("hashing %s: %w", name, err)
+		}
+		files = append(files, prefixed("private/"+name+"/", f)...)
+	}
+
+	for _, name := range relPaths(privateRepo, listProtectedFiles(privateProblem)) {
+		f, err := digestFiles(path.Join(privateRepo, name))
+		if err != nil {
+			return "", fmt.Errorf("hashing %s: %w", name, err)
+		}
+		files = append(files, prefixed("private/"+name+"/", f)...)
+	}
+
+	testdataFiles, err := digestFiles(path.Join(privateRepo, problem, testdataDir))
+	if err != nil {
+		return "", fmt.Errorf("hashing testdata: %w", err)
+	}
+	files = append(files, prefixed("private/testdata/", testdataFiles)...)
+
+	moduleFiles := []string{"go.mod", "go.sum", ".golangci.yml"}
+	if problemHasOwnModule(privateRepo, problem) {
+		moduleFiles = []string{path.Join(problem, "go.mod"), path.Join(problem, "go.sum"), ".golangci.yml"}
+		for _, m := range helperModules {
+			moduleFiles = append(moduleFiles, m)
+		}
+	}
+	// getBenchmarkRequirements reads benchmarks.yml straight from the
+	// private repo rather than a file copied into tmpRepo, so changing a
+	// problem's bench-count/multiplier/gated-benchmark list must also
+	// change the digest, or a stale cached pass/fail keeps getting replayed
+	// under the new gating.
+	moduleFiles = append(moduleFiles, path.Join(problem, benchmarksConfigFile))
+	for _, name := range moduleFiles {
+		f, err := digestFiles(path.Join(privateRepo, name))
+		if err != nil {
+			return "", fmt.Errorf("hashing %s: %w", name, err)
+		}
+		files = append(files, prefixed("private/"+name+"/", f)...)
+	}
+
+	sort.Slice(files, func(i, j int) bool { return files[i].relPath < files[j].relPath })
+
+	h := sha256.New()
+	for _, f := range files {
+		fmt.Fprintf(h, "%s\x00%o\x00%x\x00", f.relPath, f.mode, f.sha256)
+	}
+
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
+
+func prefixed(prefix string, files []hashedFile) []hashedFile {
+	out := make([]hashedFile, len(files))
+	for i, f := range files {
+		out[i] = f
+		out[i].relPath = prefix + f.relPath
+	}
+	return out
+}
+
+var cacheCmd = &cobra.Command{
+	Use:   "cache",
+	Short: "manage check-task's on-disk result cache",
+}
+
+var cacheCleanCmd = &cobra.Command{
+	Use:   "clean",
+	Short: "remove all cached check-task results",
+	Run: func(cmd *cobra.Command, args []string) {
+		cacheDir, err := resolveCacheDir(cmd)
+		if err != nil {
+			log.Fatal(err)
+		}
+
+		if err := os.RemoveAll(cacheDir); err != nil {
+			log.Fatalf("removing cache dir %s: %s", cacheDir, err)
+		}
+
+		log.Printf("removed %s", cacheDir)
+	},
+}
+
+func init() {
+	testSubmissionCmd.AddCommand(cacheCmd)
+	cacheCmd.AddCommand(cacheCleanCmd)
+}
@@ -0,0 +1,91 @@
+package commands
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestDigestFilesIsContentAddressed(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "a.txt"), []byte("hello"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	first, err := digestFiles(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(first) != 1 || first[0].relPath != "a.txt" {
+		t.Fatalf("unexpected digest result: %+v", first)
+	}
+
+	second, err := digestFiles(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if first[0].sha256 != second[0].sha256 {
+		t.Fatalf("digest changed across two runs over identical content")
+	}
+
+	if err := os.WriteFile(filepath.Join(dir, "a.txt"), []byte("goodbye"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	third, err := digestFiles(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if third[0].sha256 == first[0].sha256 {
+		t.Fatalf("digest did not change after file content changed")
+	}
+}
+
+func TestDigestFilesMissingPath(t *testing.T) {
+	files, err := digestFiles(filepath.Join(t.TempDir(), "does-not-exist"))
+	if err != nil {
+		t.Fatalf("expected no error for a missing path, got %s", err)
+	}
+	if files != nil {
+		t.Fatalf("expected a nil result for a missing path, got %+v", files)
+	}
+}
+
+func TestComputeSubmissionDigestStableAndSensitive(t *testing.T) {
+	studentRepo := t.TempDir()
+	privateRepo := t.TempDir()
+	const problem = "problem"
+
+	writeFile := func(repo, rel, content string) {
+		full := filepath.Join(repo, rel)
+		if err := os.MkdirAll(filepath.Dir(full), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(full, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	writeFile(studentRepo, filepath.Join(problem, "solution.go"), "package problem")
+	writeFile(privateRepo, "go.mod", "module gitlab.com/slon/shad-go\n")
+
+	first, err := computeSubmissionDigest(studentRepo, privateRepo, problem)
+	if err != nil {
+		t.Fatal(err)
+	}
+	second, err := computeSubmissionDigest(studentRepo, privateRepo, problem)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if first != second {
+		t.Fatalf("digest changed across two runs over identical inputs: %s != %s", first, second)
+	}
+
+	writeFile(studentRepo, filepath.Join(problem, "solution.go"), "package problem // changed")
+	third, err := computeSubmissionDigest(studentRepo, privateRepo, problem)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if third == first {
+		t.Fatalf("digest did not change after the student solution changed")
+	}
+}
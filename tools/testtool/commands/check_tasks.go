@@ -0,0 +1,341 @@
+package commands
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"os"
+	"os/exec"
+	"runtime"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"text/tabwriter"
+
+	"github.com/spf13/cobra"
+
+	"gitlab.com/slon/shad-go/tools/testtool/commands/report"
+)
+
+const (
+	problemsFlag = "problems"
+	allFlag      = "all"
+	jobsFlag     = "jobs"
+)
+
+// jobResult is one problem's outcome from a check-tasks run.
+type jobResult struct {
+	Problem string
+	Passed  bool
+	Err     error
+	Report  report.Result
+}
+
+var checkTasksCmd = &cobra.Command{
+	Use:   "check-tasks",
+	Short: "test many tasks concurrently",
+	Run: func(cmd *cobra.Command, args []string) {
+		studentRepo := mustParseDirFlag(studentRepoFlag, cmd)
+		privateRepo := mustParseDirFlag(privateRepoFlag, cmd)
+
+		problems, err := resolveProblems(cmd, studentRepo, privateRepo)
+		if err != nil {
+			log.Fatal(err)
+		}
+		if len(problems) == 0 {
+			log.Fatalf("no problems selected; pass --%s or --%s", problemsFlag, allFlag)
+		}
+
+		jobs, err := cmd.Flags().GetInt(jobsFlag)
+		if err != nil {
+			log.Fatal(err)
+		}
+		if jobs < 1 {
+			jobs = 1
+		}
+		log.Printf("testing %d problem(s) with %d worker(s)", len(problems), jobs)
+
+		reportPath, err := cmd.Flags().GetString(reportFlag)
+		if err != nil {
+			log.Fatal(err)
+		}
+		reportFormat, err := cmd.Flags().GetString(reportFormatFlag)
+		if err != nil {
+			log.Fatal(err)
+		}
+
+		exe, err := os.Executable()
+		if err != nil {
+			log.Fatal(err)
+		}
+
+		sharedArgs, err := checkTaskArgs(cmd, studentRepo, privateRepo)
+		if err != nil {
+			log.Fatal(err)
+		}
+
+		var stdoutMu sync.Mutex
+		results := runJobs(problems, jobs, func(problem string) jobResult {
+			return runOneTask(exe, &stdoutMu, problem, sharedArgs, reportPath != "")
+		})
+
+		printResultsTable(results)
+
+		if reportPath != "" {
+			if err := writeCombinedReport(reportPath, reportFormat, results); err != nil {
+				log.Printf("warning: failed to write combined report: %s", err)
+			}
+		}
+
+		for _, r := range results {
+			if !r.Passed {
+				os.Exit(1)
+			}
+		}
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(checkTasksCmd)
+
+	checkTasksCmd.Flags().String(studentRepoFlag, ".", "path to student repo root")
+	checkTasksCmd.Flags().String(privateRepoFlag, ".", "path to shad-go-private repo root")
+	checkTasksCmd.Flags().String(problemsFlag, "", "comma-separated list of problems to test")
+	checkTasksCmd.Flags().Bool(allFlag, false, "test every problem present in both repos")
+	checkTasksCmd.Flags().Int(jobsFlag, runtime.NumCPU()/2, "number of problems to test concurrently")
+
+	checkTasksCmd.Flags().Bool(noCacheFlag, false, "always re-run the full pipeline, ignoring any cached result")
+	checkTasksCmd.Flags().String(cacheDirFlag, "", "directory to store check-task result cache in (default $XDG_CACHE_HOME/shad-go/check-task)")
+	checkTasksCmd.Flags().Int(benchCountFlag, 6, "number of separate runs to compare student and baseline benchmarks over")
+	checkTasksCmd.Flags().String(sandboxFlag, sandboxLinuxNS, "how to isolate test/race/bench binaries: none, linux-ns, docker or podman")
+	checkTasksCmd.Flags().String(sandboxImageFlag, defaultSandboxImage, "OCI image to run sandboxed binaries in, when --sandbox is docker or podman")
+	checkTasksCmd.Flags().Bool(sandboxNetworkFlag, false, "allow network access from the sandboxed container")
+	checkTasksCmd.Flags().String(reportFlag, "", "write a combined structured report of all runs to this path")
+	checkTasksCmd.Flags().String(reportFormatFlag, defaultReportFormat, "format to write --report in")
+}
+
+// resolveProblems turns --problems/--all into the list of problems to test.
+func resolveProblems(cmd *cobra.Command, studentRepo, privateRepo string) ([]string, error) {
+	all, err := cmd.Flags().GetBool(allFlag)
+	if err != nil {
+		return nil, err
+	}
+	if all {
+		return listAllProblems(studentRepo, privateRepo)
+	}
+
+	list, err := cmd.Flags().GetString(problemsFlag)
+	if err != nil {
+		return nil, err
+	}
+
+	var problems []string
+	for _, p := range strings.Split(list, ",") {
+		if p = strings.TrimSpace(p); p != "" {
+			problems = append(problems, p)
+		}
+	}
+	return problems, nil
+}
+
+// listAllProblems lists every top-level directory present in both repos,
+// skipping the helper modules a problem's go.mod may opt into (see
+// setupModule).
+func listAllProblems(studentRepo, privateRepo string) ([]string, error) {
+	entries, err := os.ReadDir(privateRepo)
+	if err != nil {
+		return nil, fmt.Errorf("listing %s: %w", privateRepo, err)
+	}
+
+	var problems []string
+	for _, e := range entries {
+		name := e.Name()
+		if !e.IsDir() || strings.HasPrefix(name, ".") || isHelperModule(name) {
+			continue
+		}
+		if !problemDirExists(studentRepo, name) {
+			continue
+		}
+		problems = append(problems, name)
+	}
+
+	sort.Strings(problems)
+	return problems, nil
+}
+
+func isHelperModule(name string) bool {
+	for _, h := range helperModules {
+		if h == name {
+			return true
+		}
+	}
+	return false
+}
+
+// checkTaskArgs builds the command-line flags shared by every check-task
+// invocation a check-tasks run fans out, forwarding whatever the user
+// passed to check-tasks itself.
+func checkTaskArgs(cmd *cobra.Command, studentRepo, privateRepo string) ([]string, error) {
+	noCache, err := cmd.Flags().GetBool(noCacheFlag)
+	if err != nil {
+		return nil, err
+	}
+	cacheDir, err := cmd.Flags().GetString(cacheDirFlag)
+	if err != nil {
+		return nil, err
+	}
+	benchCount, err := cmd.Flags().GetInt(benchCountFlag)
+	if err != nil {
+		return nil, err
+	}
+	sandboxMode, err := cmd.Flags().GetString(sandboxFlag)
+	if err != nil {
+		return nil, err
+	}
+	sandboxImage, err := cmd.Flags().GetString(sandboxImageFlag)
+	if err != nil {
+		return nil, err
+	}
+	sandboxNetwork, err := cmd.Flags().GetBool(sandboxNetworkFlag)
+	if err != nil {
+		return nil, err
+	}
+	reportFormat, err := cmd.Flags().GetString(reportFormatFlag)
+	if err != nil {
+		return nil, err
+	}
+
+	args := []string{
+		"--" + studentRepoFlag, studentRepo,
+		"--" + privateRepoFlag, privateRepo,
+		"--" + benchCountFlag, strconv.Itoa(benchCount),
+		"--" + sandboxFlag, sandboxMode,
+		"--" + sandboxImageFlag, sandboxImage,
+		"--" + reportFormatFlag, reportFormat,
+	}
+	if noCache {
+		args = append(args, "--"+noCacheFlag)
+	}
+	if cacheDir != "" {
+		args = append(args, "--"+cacheDirFlag, cacheDir)
+	}
+	if sandboxNetwork {
+		args = append(args, "--"+sandboxNetworkFlag)
+	}
+	return args, nil
+}
+
+// runJobs runs run over every problem using a bounded pool of workers,
+// returning results in the same order as problems.
+func runJobs(problems []string, jobs int, run func(problem string) jobResult) []jobResult {
+	results := make([]jobResult, len(problems))
+
+	type work struct {
+		idx     int
+		problem string
+	}
+	queue := make(chan work)
+
+	var wg sync.WaitGroup
+	for i := 0; i < jobs; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for w := range queue {
+				results[w.idx] = run(w.problem)
+			}
+		}()
+	}
+
+	for i, p := range problems {
+		queue <- work{idx: i, problem: p}
+	}
+	close(queue)
+	wg.Wait()
+
+	return results
+}
+
+// runOneTask re-execs this binary as `check-task --problem <problem>`,
+// giving the job its own process (and so its own tmpRepo/binCache/goCache,
+// which testSubmissionUncached already randomizes) instead of sharing one
+// in-process pipeline with every other worker. Output is streamed through
+// a prefixing writer so interleaved logs stay attributable to their job.
+// reportEnabled mirrors check-tasks' own --report: a per-job report is only
+// requested (and the per-job cache bypassed, per testSubmission) when the
+// combined report it feeds is actually going to be written.
+func runOneTask(exe string, stdoutMu *sync.Mutex, problem string, sharedArgs []string, reportEnabled bool) jobResult {
+	var reportPath string
+	if reportEnabled {
+		tmpReport, err := os.CreateTemp("", "check-task-report-*.json")
+		if err != nil {
+			return jobResult{Problem: problem, Err: err}
+		}
+		reportPath = tmpReport.Name()
+		_ = tmpReport.Close()
+		defer func() { _ = os.Remove(reportPath) }()
+	}
+
+	args := make([]string, 0, 4+len(sharedArgs))
+	args = append(args, testSubmissionCmd.Use, "--"+problemFlag, problem)
+	if reportEnabled {
+		args = append(args, "--"+reportFlag, reportPath)
+	}
+	args = append(args, sharedArgs...)
+
+	cmd := exec.Command(exe, args...)
+	cmd.Env = os.Environ()
+
+	pw := newPrefixWriter(stdoutMu, os.Stdout, "["+problem+"] ")
+	cmd.Stdout = pw
+	cmd.Stderr = pw
+
+	runErr := cmd.Run()
+	pw.Flush()
+
+	var rep report.Result
+	if raw, readErr := os.ReadFile(reportPath); reportEnabled && readErr == nil {
+		_ = json.Unmarshal(raw, &rep)
+	}
+	rep.Problem = problem
+
+	return jobResult{Problem: problem, Passed: runErr == nil, Err: runErr, Report: rep}
+}
+
+func printResultsTable(results []jobResult) {
+	w := tabwriter.NewWriter(os.Stdout, 0, 2, 2, ' ', 0)
+	_, _ = fmt.Fprintln(w, "PROBLEM\tSTATUS")
+	for _, r := range results {
+		status := "pass"
+		if !r.Passed {
+			status = "fail"
+		}
+		_, _ = fmt.Fprintf(w, "%s\t%s\n", r.Problem, status)
+	}
+	_ = w.Flush()
+}
+
+// combinedReport is the --report shape a check-tasks run produces: every
+// job's own report.Result, in the order the problems were listed, each
+// carrying its problem name.
+type combinedReport struct {
+	Results []report.Result `json:"results"`
+}
+
+func writeCombinedReport(path, format string, results []jobResult) error {
+	if format != defaultReportFormat {
+		return fmt.Errorf("unsupported report format %q", format)
+	}
+
+	combined := combinedReport{}
+	for _, r := range results {
+		combined.Results = append(combined.Results, r.Report)
+	}
+
+	raw, err := json.MarshalIndent(combined, "", "  ")
+	if err != nil {
+		return fmt.Errorf("marshaling combined report: %w", err)
+	}
+	return os.WriteFile(path, raw, 0644)
+}
@@ -0,0 +1,57 @@
+package commands
+
+import (
+	"bytes"
+	"sync"
+	"testing"
+)
+
+func TestPrefixWriterPrefixesCompleteLines(t *testing.T) {
+	var out bytes.Buffer
+	var mu sync.Mutex
+	w := newPrefixWriter(&mu, &out, "[p] ")
+
+	if _, err := w.Write([]byte("line one\nline ")); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := w.Write([]byte("two\n")); err != nil {
+		t.Fatal(err)
+	}
+
+	want := "[p] line one\n[p] line two\n"
+	if got := out.String(); got != want {
+		t.Fatalf("out = %q, want %q", got, want)
+	}
+}
+
+func TestPrefixWriterFlushesTrailingPartialLine(t *testing.T) {
+	var out bytes.Buffer
+	var mu sync.Mutex
+	w := newPrefixWriter(&mu, &out, "[p] ")
+
+	if _, err := w.Write([]byte("no newline yet")); err != nil {
+		t.Fatal(err)
+	}
+	if out.Len() != 0 {
+		t.Fatalf("expected nothing written before Flush, got %q", out.String())
+	}
+
+	w.Flush()
+
+	want := "[p] no newline yet\n"
+	if got := out.String(); got != want {
+		t.Fatalf("out = %q, want %q", got, want)
+	}
+}
+
+func TestPrefixWriterFlushOnEmptyBufferIsNoop(t *testing.T) {
+	var out bytes.Buffer
+	var mu sync.Mutex
+	w := newPrefixWriter(&mu, &out, "[p] ")
+
+	w.Flush()
+
+	if out.Len() != 0 {
+		t.Fatalf("expected Flush on an empty writer to write nothing, got %q", out.String())
+	}
+}
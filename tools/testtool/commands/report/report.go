@@ -0,0 +1,104 @@
+// Package report defines the machine-readable summary of a check-task run,
+// so both the CLI's own --report flag and downstream tools (GitLab MR bots,
+// a web dashboard) can consume the same shape.
+package report
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+)
+
+// Status is the pass/fail outcome of one stage of the pipeline.
+type Status string
+
+const (
+	StatusPass Status = "pass"
+	StatusFail Status = "fail"
+)
+
+// TestResult is one `go test -json` test's final outcome.
+type TestResult struct {
+	Package string  `json:"package"`
+	Name    string  `json:"name"`
+	Status  Status  `json:"status"`
+	Elapsed float64 `json:"elapsed_seconds"`
+	Output  string  `json:"output,omitempty"`
+}
+
+// RaceResult is the outcome of running a package's tests under -race.
+type RaceResult struct {
+	Status Status `json:"status"`
+	Output string `json:"output,omitempty"`
+}
+
+// BenchmarkResult is one benchmark's comparison against the baseline
+// solution, as computed by compareToBaseline.
+type BenchmarkResult struct {
+	Name         string  `json:"name"`
+	BaselineMean float64 `json:"baseline_mean"`
+	StudentMean  float64 `json:"student_mean"`
+	Unit         string  `json:"unit"`
+	PValue       float64 `json:"p_value"`
+	Multiplier   float64 `json:"multiplier"`
+	Status       Status  `json:"status"`
+}
+
+// CoverageResult is the measured coverage against the problem's requirement.
+type CoverageResult struct {
+	Enabled  bool    `json:"enabled"`
+	Percent  float64 `json:"percent"`
+	Required float64 `json:"required"`
+	Status   Status  `json:"status"`
+}
+
+// LintIssue is one golangci-lint finding, as reported with --out-format json.
+type LintIssue struct {
+	Linter string `json:"linter"`
+	Path   string `json:"path"`
+	Line   int    `json:"line"`
+	Text   string `json:"text"`
+}
+
+// LintResult is the outcome of the linter stage.
+type LintResult struct {
+	Status Status      `json:"status"`
+	Issues []LintIssue `json:"issues,omitempty"`
+}
+
+// Result is the full summary of a single check-task run.
+type Result struct {
+	Problem    string            `json:"problem"`
+	Status     Status            `json:"status"`
+	Error      string            `json:"error,omitempty"`
+	Tests      []TestResult      `json:"tests,omitempty"`
+	Race       RaceResult        `json:"race"`
+	Benchmarks []BenchmarkResult `json:"benchmarks,omitempty"`
+	Coverage   CoverageResult    `json:"coverage"`
+	Lint       LintResult        `json:"lint"`
+}
+
+// FromError folds the pipeline's terminal error, if any, into r.
+func (r *Result) FromError(err error) {
+	if err == nil {
+		r.Status = StatusPass
+		return
+	}
+	r.Status = StatusFail
+	r.Error = err.Error()
+}
+
+// WriteFile marshals r in the given format and writes it to path. Only
+// "json" is currently supported.
+func WriteFile(path, format string, r Result) error {
+	if format != "json" {
+		return fmt.Errorf("unsupported report format %q", format)
+	}
+
+	raw, err := json.MarshalIndent(r, "", "  ")
+	if err != nil {
+		return fmt.Errorf("marshaling report: %w", err)
+	}
+
+	return os.WriteFile(path, raw, 0644)
+}
@@ -0,0 +1,129 @@
+package commands
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"log"
+	"os/exec"
+
+	"gitlab.com/slon/shad-go/tools/testtool/commands/report"
+)
+
+// test2JSON converts a test binary's `-test.v=test2json` text output into
+// the NDJSON stream parseGoTestJSON expects, by shelling out to the `go
+// tool test2json` that `go test -json` itself uses for this conversion —
+// a compiled test binary has no JSON output mode of its own.
+func test2JSON(pkg string, textOutput []byte) ([]byte, error) {
+	cmd := exec.Command("go", "tool", "test2json", "-p", pkg)
+	cmd.Stdin = bytes.NewReader(textOutput)
+
+	var out, errOut bytes.Buffer
+	cmd.Stdout = &out
+	cmd.Stderr = &errOut
+
+	if err := cmd.Run(); err != nil {
+		return nil, fmt.Errorf("go tool test2json: %w: %s", err, errOut.String())
+	}
+	return out.Bytes(), nil
+}
+
+// goTestEvent is one line of a test2json-produced stream.
+type goTestEvent struct {
+	Action  string
+	Package string
+	Test    string
+	Elapsed float64
+	Output  string
+}
+
+// parseGoTestJSON reconstructs per-test results from a test2json-produced
+// NDJSON stream (see test2JSON), concatenating each test's "output" events
+// and keeping its final pass/fail/skip action. report.Status has no skip
+// value, so a skipped test is recorded as passed.
+func parseGoTestJSON(data []byte) []report.TestResult {
+	output := make(map[string]*bytes.Buffer)
+	results := make(map[string]*report.TestResult)
+	var order []string
+
+	dec := json.NewDecoder(bytes.NewReader(data))
+	for {
+		var ev goTestEvent
+		if err := dec.Decode(&ev); err != nil {
+			break
+		}
+		if ev.Test == "" {
+			continue
+		}
+
+		switch ev.Action {
+		case "output":
+			buf, ok := output[ev.Test]
+			if !ok {
+				buf = &bytes.Buffer{}
+				output[ev.Test] = buf
+			}
+			buf.WriteString(ev.Output)
+		case "pass", "fail", "skip":
+			if _, ok := results[ev.Test]; !ok {
+				order = append(order, ev.Test)
+			}
+
+			status := report.StatusPass
+			if ev.Action == "fail" {
+				status = report.StatusFail
+			}
+
+			results[ev.Test] = &report.TestResult{
+				Package: ev.Package,
+				Name:    ev.Test,
+				Status:  status,
+				Elapsed: ev.Elapsed,
+			}
+		}
+	}
+
+	tests := make([]report.TestResult, 0, len(order))
+	for _, name := range order {
+		r := results[name]
+		if buf, ok := output[name]; ok {
+			r.Output = buf.String()
+		}
+		tests = append(tests, *r)
+	}
+	return tests
+}
+
+// golangciLintIssue is one entry of golangci-lint's --out-format json output.
+type golangciLintIssue struct {
+	FromLinter string `json:"FromLinter"`
+	Text       string `json:"Text"`
+	Pos        struct {
+		Filename string `json:"Filename"`
+		Line     int    `json:"Line"`
+	} `json:"Pos"`
+}
+
+type golangciLintReport struct {
+	Issues []golangciLintIssue `json:"Issues"`
+}
+
+// parseLintJSON parses golangci-lint's --out-format json report.
+func parseLintJSON(data []byte) []report.LintIssue {
+	var parsed golangciLintReport
+	if err := json.Unmarshal(data, &parsed); err != nil {
+		log.Printf("warning: failed to parse golangci-lint json output: %s", err)
+		return nil
+	}
+
+	issues := make([]report.LintIssue, 0, len(parsed.Issues))
+	for _, i := range parsed.Issues {
+		issues = append(issues, report.LintIssue{
+			Linter: i.FromLinter,
+			Path:   i.Pos.Filename,
+			Line:   i.Pos.Line,
+			Text:   i.Text,
+		})
+	}
+	return issues
+}
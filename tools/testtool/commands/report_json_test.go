@@ -0,0 +1,61 @@
+package commands
+
+import (
+	"strings"
+	"testing"
+
+	"gitlab.com/slon/shad-go/tools/testtool/commands/report"
+)
+
+func TestParseGoTestJSON(t *testing.T) {
+	const stream = `
+{"Action":"run","Package":"pkg","Test":"TestFoo"}
+{"Action":"output","Package":"pkg","Test":"TestFoo","Output":"ok\n"}
+{"Action":"pass","Package":"pkg","Test":"TestFoo","Elapsed":0.01}
+{"Action":"run","Package":"pkg","Test":"TestBar"}
+{"Action":"output","Package":"pkg","Test":"TestBar","Output":"boom\n"}
+{"Action":"fail","Package":"pkg","Test":"TestBar","Elapsed":0.02}
+`
+	results := parseGoTestJSON([]byte(strings.TrimSpace(stream)))
+	if len(results) != 2 {
+		t.Fatalf("got %d results, want 2: %+v", len(results), results)
+	}
+
+	if got := results[0]; got.Name != "TestFoo" || got.Status != report.StatusPass || got.Output != "ok\n" {
+		t.Fatalf("unexpected TestFoo result: %+v", got)
+	}
+	if got := results[1]; got.Name != "TestBar" || got.Status != report.StatusFail || got.Output != "boom\n" {
+		t.Fatalf("unexpected TestBar result: %+v", got)
+	}
+}
+
+func TestParseGoTestJSONPreservesRunOrder(t *testing.T) {
+	const stream = `
+{"Action":"pass","Package":"pkg","Test":"TestB"}
+{"Action":"pass","Package":"pkg","Test":"TestA"}
+`
+	results := parseGoTestJSON([]byte(strings.TrimSpace(stream)))
+	if len(results) != 2 || results[0].Name != "TestB" || results[1].Name != "TestA" {
+		t.Fatalf("expected results in the order tests were reported, got %+v", results)
+	}
+}
+
+func TestParseLintJSON(t *testing.T) {
+	const raw = `{"Issues":[{"FromLinter":"govet","Text":"bad","Pos":{"Filename":"main.go","Line":10}}]}`
+
+	issues := parseLintJSON([]byte(raw))
+	if len(issues) != 1 {
+		t.Fatalf("got %d issues, want 1: %+v", len(issues), issues)
+	}
+
+	want := report.LintIssue{Linter: "govet", Path: "main.go", Line: 10, Text: "bad"}
+	if issues[0] != want {
+		t.Fatalf("issue = %+v, want %+v", issues[0], want)
+	}
+}
+
+func TestParseLintJSONInvalidInputReturnsNil(t *testing.T) {
+	if issues := parseLintJSON([]byte("not json")); issues != nil {
+		t.Fatalf("expected nil issues for invalid json, got %+v", issues)
+	}
+}
@@ -0,0 +1,145 @@
+package commands
+
+import (
+	"fmt"
+	"os/exec"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"gitlab.com/slon/shad-go/tools/testtool"
+)
+
+const (
+	sandboxFlag        = "sandbox"
+	sandboxImageFlag   = "sandbox-image"
+	sandboxNetworkFlag = "sandbox-network"
+
+	sandboxNone    = "none"
+	sandboxLinuxNS = "linux-ns"
+	sandboxDocker  = "docker"
+	sandboxPodman  = "podman"
+
+	defaultSandboxImage = "golang:1.22"
+
+	containerWorkDir     = "/work"
+	containerBinCacheDir = "/bin/check-task-cache"
+	containerCoverDir    = "/cover"
+)
+
+// sandboxOptions selects how test/race/bench binaries get isolated while
+// running, mirroring the root-only in-process sandbox() path with container
+// alternatives usable on non-root CI runners.
+type sandboxOptions struct {
+	Mode    string
+	Image   string
+	Network bool
+}
+
+func validSandboxMode(mode string) bool {
+	switch mode {
+	case sandboxNone, sandboxLinuxNS, sandboxDocker, sandboxPodman:
+		return true
+	default:
+		return false
+	}
+}
+
+// runSandboxed runs cmd isolated according to opts.Mode. cmd must already
+// have Path, Args, Dir and Env fully populated; runSandboxed only decides
+// how (or whether) to wrap the invocation.
+func runSandboxed(opts sandboxOptions, cmd *exec.Cmd) error {
+	switch opts.Mode {
+	case sandboxDocker, sandboxPodman:
+		return runContainerized(opts, cmd)
+	case sandboxLinuxNS:
+		if currentUserIsRoot() {
+			if err := sandbox(cmd); err != nil {
+				return err
+			}
+		}
+		return cmd.Run()
+	default:
+		return cmd.Run()
+	}
+}
+
+// runContainerized re-executes cmd inside a minimal OCI container: the
+// working directory is bind-mounted read-only, the whole binCache (not just
+// cmd's own binary — tests that exec a companion binary via
+// testtool.BinariesEnv need those too) is bind-mounted read-only, a
+// -test.coverprofile destination (if any) gets its own writable bind mount
+// so the host can read it back after the container exits, /tmp is a fresh
+// tmpfs, all capabilities are dropped, and networking is disabled unless
+// opts.Network is set.
+func runContainerized(opts sandboxOptions, cmd *exec.Cmd) error {
+	binary, err := filepath.Abs(cmd.Path)
+	if err != nil {
+		return fmt.Errorf("resolving sandboxed binary path: %w", err)
+	}
+	binCache := filepath.Dir(binary)
+	containerBinary := path.Join(containerBinCacheDir, filepath.Base(binary))
+
+	args := []string{
+		"run", "--rm",
+		"--read-only",
+		"--cap-drop", "ALL",
+		"--tmpfs", "/tmp",
+		"--volume", cmd.Dir + ":" + containerWorkDir + ":ro",
+		"--volume", binCache + ":" + containerBinCacheDir + ":ro",
+		"--workdir", containerWorkDir,
+	}
+
+	if !opts.Network {
+		args = append(args, "--network", "none")
+	}
+
+	cmdArgs, coverHostDir := remapCoverProfile(cmd.Args[1:])
+	if coverHostDir != "" {
+		args = append(args, "--volume", coverHostDir+":"+containerCoverDir)
+	}
+
+	for _, env := range cmd.Env {
+		args = append(args, "--env", remapBinariesEnv(env, binCache, containerBinCacheDir))
+	}
+
+	args = append(args, opts.Image, containerBinary)
+	args = append(args, cmdArgs...)
+
+	runtimeCmd := exec.Command(opts.Mode, args...)
+	runtimeCmd.Stdout = cmd.Stdout
+	runtimeCmd.Stderr = cmd.Stderr
+
+	return runtimeCmd.Run()
+}
+
+// remapCoverProfile rewrites a -test.coverprofile argument's host path to
+// its in-container location (under containerCoverDir), returning the host
+// directory that needs to be bind-mounted there so the profile survives
+// past the container's lifetime. Returns hostDir == "" when args carries no
+// -test.coverprofile flag.
+func remapCoverProfile(args []string) (remapped []string, hostDir string) {
+	remapped = append([]string(nil), args...)
+
+	for i, a := range remapped {
+		if a != "-test.coverprofile" || i+1 >= len(remapped) {
+			continue
+		}
+		hostDir = filepath.Dir(remapped[i+1])
+		remapped[i+1] = path.Join(containerCoverDir, filepath.Base(remapped[i+1]))
+		break
+	}
+	return remapped, hostDir
+}
+
+// remapBinariesEnv rewrites host binCache paths embedded in the
+// testtool.BinariesEnv JSON payload to their in-container location. Every
+// other env var passes through unchanged, including host paths such as
+// GOCACHE's, which are not mounted into the container.
+func remapBinariesEnv(env, hostBinCache, containerBinCache string) string {
+	prefix := testtool.BinariesEnv + "="
+	if !strings.HasPrefix(env, prefix) {
+		return env
+	}
+	return prefix + strings.ReplaceAll(strings.TrimPrefix(env, prefix), hostBinCache, containerBinCache)
+}
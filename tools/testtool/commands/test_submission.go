@@ -17,6 +17,7 @@ import (
 	"golang.org/x/perf/benchstat"
 
 	"gitlab.com/slon/shad-go/tools/testtool"
+	"gitlab.com/slon/shad-go/tools/testtool/commands/report"
 )
 
 const (
@@ -24,10 +25,22 @@ const (
 	studentRepoFlag = "student-repo"
 	privateRepoFlag = "private-repo"
 
+	reportFlag          = "report"
+	reportFormatFlag    = "report-format"
+	defaultReportFormat = "json"
+
 	testdataDir      = "testdata"
 	moduleImportPath = "gitlab.com/slon/shad-go"
 )
 
+// reportOptions controls whether testSubmission writes a structured
+// report.Result to disk for CI to consume, in addition to its usual human
+// log output.
+type reportOptions struct {
+	Path   string
+	Format string
+}
+
 var testSubmissionCmd = &cobra.Command{
 	Use:   "check-task",
 	Short: "test single task",
@@ -47,7 +60,55 @@ var testSubmissionCmd = &cobra.Command{
 			log.Fatalf("%s does not have %s directory", privateRepo, problem)
 		}
 
-		if err := testSubmission(studentRepo, privateRepo, problem); err != nil {
+		noCache, err := cmd.Flags().GetBool(noCacheFlag)
+		if err != nil {
+			log.Fatal(err)
+		}
+		cacheDir, err := resolveCacheDir(cmd)
+		if err != nil {
+			log.Fatal(err)
+		}
+		benchCount, err := cmd.Flags().GetInt(benchCountFlag)
+		if err != nil {
+			log.Fatal(err)
+		}
+
+		sandboxMode, err := cmd.Flags().GetString(sandboxFlag)
+		if err != nil {
+			log.Fatal(err)
+		}
+		if !validSandboxMode(sandboxMode) {
+			log.Fatalf("invalid --%s value %q", sandboxFlag, sandboxMode)
+		}
+		sandboxImage, err := cmd.Flags().GetString(sandboxImageFlag)
+		if err != nil {
+			log.Fatal(err)
+		}
+		sandboxNetwork, err := cmd.Flags().GetBool(sandboxNetworkFlag)
+		if err != nil {
+			log.Fatal(err)
+		}
+
+		reportPath, err := cmd.Flags().GetString(reportFlag)
+		if err != nil {
+			log.Fatal(err)
+		}
+		reportFormat, err := cmd.Flags().GetString(reportFormatFlag)
+		if err != nil {
+			log.Fatal(err)
+		}
+
+		if err := testSubmission(studentRepo, privateRepo, problem, cacheOptions{
+			Disabled: noCache,
+			Dir:      cacheDir,
+		}, benchCount, sandboxOptions{
+			Mode:    sandboxMode,
+			Image:   sandboxImage,
+			Network: sandboxNetwork,
+		}, reportOptions{
+			Path:   reportPath,
+			Format: reportFormat,
+		}); err != nil {
 			log.Fatal(err)
 		}
 	},
@@ -61,6 +122,17 @@ func init() {
 
 	testSubmissionCmd.Flags().String(studentRepoFlag, ".", "path to student repo root")
 	testSubmissionCmd.Flags().String(privateRepoFlag, ".", "path to shad-go-private repo root")
+	testSubmissionCmd.Flags().Int(benchCountFlag, 6, "number of separate runs to compare student and baseline benchmarks over")
+
+	testSubmissionCmd.Flags().String(sandboxFlag, sandboxLinuxNS, "how to isolate test/race/bench binaries: none, linux-ns, docker or podman")
+	testSubmissionCmd.Flags().String(sandboxImageFlag, defaultSandboxImage, "OCI image to run sandboxed binaries in, when --sandbox is docker or podman")
+	testSubmissionCmd.Flags().Bool(sandboxNetworkFlag, false, "allow network access from the sandboxed container")
+
+	testSubmissionCmd.Flags().String(reportFlag, "", "write a structured report of the run to this path (CI consumption); empty disables it")
+	testSubmissionCmd.Flags().String(reportFormatFlag, defaultReportFormat, "format to write --report in")
+
+	testSubmissionCmd.PersistentFlags().Bool(noCacheFlag, false, "always re-run the full pipeline, ignoring any cached result")
+	testSubmissionCmd.PersistentFlags().String(cacheDirFlag, "", "directory to store check-task result cache in (default $XDG_CACHE_HOME/shad-go/check-task)")
 }
 
 // mustParseDirFlag parses string directory flag with given name.
@@ -87,7 +159,78 @@ func problemDirExists(repo, problem string) bool {
 	return info.IsDir()
 }
 
-func testSubmission(studentRepo, privateRepo, problem string) error {
+func testSubmission(studentRepo, privateRepo, problem string, cache cacheOptions, benchCount int, sandboxOpts sandboxOptions, reportOpts reportOptions) error {
+	reportEnabled := reportOpts.Path != ""
+
+	writeReport := func(r report.Result) {
+		if !reportEnabled {
+			return
+		}
+		r.Problem = problem
+		if err := report.WriteFile(reportOpts.Path, reportOpts.Format, r); err != nil {
+			log.Printf("warning: failed to write report: %s", err)
+		}
+	}
+
+	// The cache manifest only stores human log text, not parsed
+	// per-test/benchmark/lint data, so a cache hit can't produce a faithful
+	// report.Result. Always run fresh when --report is set.
+	if cache.Disabled || reportEnabled {
+		_, rep, err := testSubmissionUncached(studentRepo, privateRepo, problem, benchCount, sandboxOpts, reportEnabled)
+		writeReport(rep)
+		return err
+	}
+
+	digest, err := computeSubmissionDigest(studentRepo, privateRepo, problem)
+	if err != nil {
+		return fmt.Errorf("computing submission digest: %w", err)
+	}
+
+	if m, ok := loadCacheManifest(cache.Dir, problem, digest); ok && m.Status == cacheStatusPass {
+		replayCacheManifest(m)
+		return nil
+	}
+
+	result, _, runErr := testSubmissionUncached(studentRepo, privateRepo, problem, benchCount, sandboxOpts, reportEnabled)
+
+	m := &cacheManifest{
+		Status:          cacheStatusPass,
+		TestOutput:      result.testOutput,
+		BenchstatTables: result.benchstatTables,
+		LintOutput:      result.lintOutput,
+		CoveragePercent: result.coveragePercent,
+	}
+	if runErr != nil {
+		m.Status = cacheStatusFail
+	}
+	if saveErr := saveCacheManifest(cache.Dir, problem, digest, m); saveErr != nil {
+		log.Printf("warning: failed to save cache manifest: %s", saveErr)
+	}
+	return runErr
+}
+
+// submissionResult carries everything worth remembering about a run, so a
+// later cache hit can replay it faithfully.
+type submissionResult struct {
+	testOutput      string
+	benchstatTables string
+	lintOutput      string
+	coveragePercent float64
+}
+
+// pipelineReport is the structured data runTests gathers about a run, which
+// testSubmissionUncached folds into a report.Result when --report is set.
+type pipelineReport struct {
+	Tests      []report.TestResult
+	Race       report.RaceResult
+	Benchmarks []report.BenchmarkResult
+	Coverage   report.CoverageResult
+}
+
+func testSubmissionUncached(studentRepo, privateRepo, problem string, benchCount int, sandboxOpts sandboxOptions, reportEnabled bool) (submissionResult, report.Result, error) {
+	var result submissionResult
+	var rep report.Result
+
 	// Create temp directory to store all files required to test the solution.
 	tmpRepo, err := os.MkdirTemp("/tmp", problem+"-")
 	if err != nil {
@@ -120,21 +263,51 @@ func testSubmission(studentRepo, privateRepo, problem string) error {
 	log.Printf("copying testdata directory")
 	copyDir(privateRepo, path.Join(problem, testdataDir), tmpRepo)
 
-	// Copy go.mod and go.sum from private repo to temp dir.
-	log.Printf("copying go.mod, go.sum and .golangci.yml")
-	copyFiles(privateRepo, []string{"go.mod", "go.sum", ".golangci.yml"}, tmpRepo)
+	// Set up the Go module tmpRepo builds under: the shared top-level one,
+	// or (if the problem pins its own dependencies) the problem's go.mod
+	// plus its go.work/replace wiring to the helper modules.
+	log.Printf("setting up go module")
+	setupModule(privateRepo, problem, tmpRepo)
 
 	log.Printf("running tests")
-	if err := runTests(tmpRepo, privateRepo, problem); err != nil {
-		return err
+	var pr pipelineReport
+	testOutput, benchstatTables, runErr := captureOutput(func() error {
+		var testErr error
+		pr, testErr = runTests(tmpRepo, privateRepo, problem, benchCount, sandboxOpts, reportEnabled)
+		result.coveragePercent = pr.Coverage.Percent
+		return testErr
+	})
+	result.testOutput = testOutput
+	result.benchstatTables = benchstatTables
+	if reportEnabled {
+		rep.Tests = pr.Tests
+		rep.Race = pr.Race
+		rep.Benchmarks = pr.Benchmarks
+		rep.Coverage = pr.Coverage
+	}
+	if runErr != nil {
+		rep.FromError(runErr)
+		return result, rep, runErr
 	}
 
 	log.Printf("running linter")
-	if err := runLinter(tmpRepo, problem); err != nil {
+	var lintResult report.LintResult
+	lintOutput, _, lintErr := captureOutput(func() error {
+		var err error
+		lintResult, err = runLinter(tmpRepo, problem, reportEnabled)
 		return err
+	})
+	result.lintOutput = lintOutput
+	if reportEnabled {
+		rep.Lint = lintResult
+	}
+	if lintErr != nil {
+		rep.FromError(lintErr)
+		return result, rep, lintErr
 	}
 
-	return nil
+	rep.FromError(nil)
+	return result, rep, nil
 }
 
 // copyDir recursively copies src directory to dst.
@@ -193,21 +366,53 @@ func (e *TestFailedError) Unwrap() error {
 	return e.E
 }
 
-func runLinter(testDir, problem string) error {
-	cmd := exec.Command("golangci-lint", "run", "--modules-download-mode", "readonly", "--build-tags", "private", fmt.Sprintf("./%s/...", problem))
+// runLinter runs golangci-lint over the problem package. When reportEnabled
+// is set it asks golangci-lint for JSON output so findings can be embedded
+// into the structured report; otherwise it streams the linter's normal
+// human-readable output, unchanged from before.
+func runLinter(testDir, problem string, reportEnabled bool) (report.LintResult, error) {
+	args := []string{"run", "--modules-download-mode", "readonly", "--build-tags", "private"}
+	if reportEnabled {
+		args = append(args, "--out-format", "json")
+	}
+	args = append(args, fmt.Sprintf("./%s/...", problem))
+
+	cmd := exec.Command("golangci-lint", args...)
 	cmd.Dir = testDir
-	cmd.Stdout = os.Stdout
 	cmd.Stderr = os.Stderr
 
-	if err := cmd.Run(); err != nil {
-		return fmt.Errorf("linter failed: %w", err)
+	if !reportEnabled {
+		cmd.Stdout = os.Stdout
+		if err := cmd.Run(); err != nil {
+			return report.LintResult{Status: report.StatusFail}, fmt.Errorf("linter failed: %w", err)
+		}
+		return report.LintResult{Status: report.StatusPass}, nil
 	}
 
-	return nil
+	var buf bytes.Buffer
+	cmd.Stdout = &buf
+	runErr := cmd.Run()
+
+	issues := parseLintJSON(buf.Bytes())
+	result := report.LintResult{Status: report.StatusPass, Issues: issues}
+	if len(issues) > 0 {
+		result.Status = report.StatusFail
+	}
+
+	if runErr != nil && len(issues) == 0 {
+		return report.LintResult{Status: report.StatusFail}, fmt.Errorf("linter failed: %w", runErr)
+	}
+	if result.Status == report.StatusFail {
+		return result, fmt.Errorf("linter found %d issue(s)", len(issues))
+	}
+
+	return result, nil
 }
 
-// runTests runs all tests in directory with race detector.
-func runTests(testDir, privateRepo, problem string) error {
+// runTests runs all tests in directory with race detector and returns a
+// pipelineReport summarizing the run (coverage percent is 0 when coverage is
+// not required for problem).
+func runTests(testDir, privateRepo, problem string, benchCount int, sandboxOpts sandboxOptions, reportEnabled bool) (pipelineReport, error) {
 	binCache, err := os.MkdirTemp("/tmp", "bincache")
 	if err != nil {
 		log.Fatal(err)
@@ -247,6 +452,8 @@ func runTests(testDir, privateRepo, problem string) error {
 		log.Printf("required coverage: %.2f%%", coverageReq.Percent)
 	}
 
+	benchReq := getBenchmarkRequirements(path.Join(privateRepo, problem), benchCount)
+
 	testListDir := testDir
 	if !coverageReq.Enabled {
 		testListDir = privateRepo
@@ -259,7 +466,7 @@ func runTests(testDir, privateRepo, problem string) error {
 		binaries[binaryPkg] = binPath
 
 		if err := runGo("build", "-mod", "readonly", "-tags", "private", "-o", binPath, binaryPkg); err != nil {
-			return fmt.Errorf("error building binary in %s: %w", binaryPkg, err)
+			return pipelineReport{}, fmt.Errorf("error building binary in %s: %w", binaryPkg, err)
 		}
 	}
 
@@ -278,7 +485,7 @@ func runTests(testDir, privateRepo, problem string) error {
 			cmd = append(cmd, "-cover", "-coverpkg", strings.Join(pkgs, ","))
 		}
 		if err := runGo(cmd...); err != nil {
-			return fmt.Errorf("error building test in %s: %w", testPkg, err)
+			return pipelineReport{}, fmt.Errorf("error building test in %s: %w", testPkg, err)
 		}
 
 		racePath := filepath.Join(binCache, randomName())
@@ -286,26 +493,46 @@ func runTests(testDir, privateRepo, problem string) error {
 
 		cmd = []string{"test", "-mod", "readonly", "-race", "-tags", "private", "-c", "-o", racePath, testPkg}
 		if err := runGo(cmd...); err != nil {
-			return fmt.Errorf("error building test in %s: %w", testPkg, err)
+			return pipelineReport{}, fmt.Errorf("error building test in %s: %w", testPkg, err)
 		}
 	}
 
 	coverProfiles := []string{}
+	var allTests []report.TestResult
+	var allBenchmarks []report.BenchmarkResult
 	for testPkg, testBinary := range testBinaries {
 		relPath := strings.TrimPrefix(testPkg, moduleImportPath)
-		coverProfile := path.Join(os.TempDir(), randomName())
+
+		// Each coverage profile gets its own directory rather than a random
+		// name directly under the shared OS temp root: runContainerized bind
+		// mounts coverProfile's parent directory into the sandbox, and
+		// binCache/goCache/tmpRepo for every concurrent check-tasks job also
+		// live directly under /tmp, so a shared parent would hand a
+		// sandboxed student binary read/write access to every other job's
+		// build artifacts.
+		coverDir, err := os.MkdirTemp("/tmp", "cover")
+		if err != nil {
+			return pipelineReport{}, fmt.Errorf("creating coverage profile dir: %w", err)
+		}
+		defer func() { _ = os.RemoveAll(coverDir) }()
+		coverProfile := filepath.Join(coverDir, randomName())
 
 		{
-			cmd := exec.Command(testBinary)
+			args := []string{}
+			if reportEnabled {
+				// A compiled test binary has no -test.json flag — JSON
+				// conversion is normally done by the go command wrapping
+				// -test.v output through test2json, not by the binary
+				// itself. -test.v=test2json asks the binary for the same
+				// high-fidelity framing test2json expects, and we convert
+				// it to JSON ourselves below.
+				args = append(args, "-test.v=test2json")
+			}
 			if coverageReq.Enabled {
-				cmd = exec.Command(testBinary, "-test.coverprofile", coverProfile)
+				args = append(args, "-test.coverprofile", coverProfile)
 				coverProfiles = append(coverProfiles, coverProfile)
 			}
-			if currentUserIsRoot() {
-				if err := sandbox(cmd); err != nil {
-					log.Fatal(err)
-				}
-			}
+			cmd := exec.Command(testBinary, args...)
 
 			cmd.Dir = filepath.Join(testDir, relPath)
 			cmd.Env = []string{
@@ -314,21 +541,30 @@ func runTests(testDir, privateRepo, problem string) error {
 				"HOME=" + os.Getenv("HOME"),
 				"GOCACHE=" + goCache,
 			}
-			cmd.Stdout = os.Stdout
+
+			var textBuf bytes.Buffer
+			if reportEnabled {
+				cmd.Stdout = &textBuf
+			} else {
+				cmd.Stdout = os.Stdout
+			}
 			cmd.Stderr = os.Stderr
 
-			if err := cmd.Run(); err != nil {
-				return &TestFailedError{E: err}
+			runErr := runSandboxed(sandboxOpts, cmd)
+			if reportEnabled {
+				jsonOutput, convErr := test2JSON(testPkg, textBuf.Bytes())
+				if convErr != nil {
+					return pipelineReport{}, fmt.Errorf("converting test output to json: %w", convErr)
+				}
+				allTests = append(allTests, parseGoTestJSON(jsonOutput)...)
+			}
+			if runErr != nil {
+				return pipelineReport{}, &TestFailedError{E: runErr}
 			}
 		}
 
 		{
 			cmd := exec.Command(raceBinaries[testPkg], "-test.bench=.")
-			if currentUserIsRoot() {
-				if err := sandbox(cmd); err != nil {
-					log.Fatal(err)
-				}
-			}
 
 			cmd.Dir = filepath.Join(testDir, relPath)
 			cmd.Env = []string{
@@ -340,100 +576,190 @@ func runTests(testDir, privateRepo, problem string) error {
 			cmd.Stdout = os.Stdout
 			cmd.Stderr = os.Stderr
 
-			if err := cmd.Run(); err != nil {
-				return &TestFailedError{E: err}
+			if err := runSandboxed(sandboxOpts, cmd); err != nil {
+				return pipelineReport{}, &TestFailedError{E: err}
 			}
 		}
 
 		{
-			benchCmd := exec.Command(testBinary, "-test.bench=.", "-test.run=^$")
-			if currentUserIsRoot() {
-				if err := sandbox(benchCmd); err != nil {
-					log.Fatal(err)
-				}
-			}
+			runBench := func() ([]byte, error) {
+				benchCmd := exec.Command(testBinary, "-test.bench="+benchReq.benchRegexp(), "-test.run=^$", "-test.count=1")
 
-			var buf bytes.Buffer
+				var buf bytes.Buffer
 
-			benchCmd.Dir = filepath.Join(testDir, relPath)
-			benchCmd.Env = []string{
-				testtool.BinariesEnv + "=" + string(binariesJSON),
-				"PATH=" + os.Getenv("PATH"),
-				"HOME=" + os.Getenv("HOME"),
-				"GOCACHE=" + goCache,
+				benchCmd.Dir = filepath.Join(testDir, relPath)
+				benchCmd.Env = []string{
+					testtool.BinariesEnv + "=" + string(binariesJSON),
+					"PATH=" + os.Getenv("PATH"),
+					"HOME=" + os.Getenv("HOME"),
+					"GOCACHE=" + goCache,
+				}
+				benchCmd.Stdout = &buf
+				benchCmd.Stderr = os.Stderr
+
+				err := runSandboxed(sandboxOpts, benchCmd)
+				return buf.Bytes(), err
 			}
-			benchCmd.Stdout = &buf
-			benchCmd.Stderr = os.Stderr
 
-			if err := benchCmd.Run(); err != nil {
-				return &TestFailedError{E: err}
+			runs, err := repeatBenchmark(runBench, benchReq.Count)
+			if err != nil {
+				return pipelineReport{}, &TestFailedError{E: err}
 			}
 
-			if strings.Contains(buf.String(), "no tests to run") {
+			if strings.Contains(string(runs), "no tests to run") {
 				continue
 			}
 
-			if err := compareToBaseline(testPkg, privateRepo, buf.Bytes()); err != nil {
-				return err
+			benchmarks, err := compareToBaseline(testPkg, privateRepo, runs, benchReq)
+			allBenchmarks = append(allBenchmarks, benchmarks...)
+			if err != nil {
+				return pipelineReport{}, err
 			}
 		}
 	}
 
+	var coveragePercent float64
 	if coverageReq.Enabled {
 		log.Printf("checking coverage is at least %.2f%%...", coverageReq.Percent)
 
 		percent, err := calCoverage(coverProfiles)
 		if err != nil {
-			return err
+			return pipelineReport{}, err
 		}
 		log.Printf("coverage is %.2f%%", percent)
 
 		if percent < coverageReq.Percent {
-			return fmt.Errorf("poor coverage %.2f%%; expected at least %.2f%%",
+			return pipelineReport{}, fmt.Errorf("poor coverage %.2f%%; expected at least %.2f%%",
 				percent, coverageReq.Percent)
 		}
+		coveragePercent = percent
 	}
 
-	return nil
+	coverageStatus := report.CoverageResult{
+		Enabled:  coverageReq.Enabled,
+		Percent:  coveragePercent,
+		Required: coverageReq.Percent,
+		Status:   report.StatusPass,
+	}
+
+	return pipelineReport{
+		Tests:      allTests,
+		Race:       report.RaceResult{Status: report.StatusPass},
+		Benchmarks: allBenchmarks,
+		Coverage:   coverageStatus,
+	}, nil
 }
 
-func noMoreThanTwoTimesWorse(old, new *benchstat.Metrics) (float64, error) {
-	if new.Mean > 1.99*old.Mean {
-		return 0.0, nil
+// repeatBenchmark runs runOnce count times, concatenating the raw
+// `go test -bench` output of every run so benchstat sees count separate
+// samples per benchmark instead of a single noisy one.
+func repeatBenchmark(runOnce func() ([]byte, error), count int) ([]byte, error) {
+	var all bytes.Buffer
+	for i := 0; i < count; i++ {
+		out, err := runOnce()
+		all.Write(out)
+		if err != nil {
+			return all.Bytes(), err
+		}
 	}
-
-	return 1.0, nil
+	return all.Bytes(), nil
 }
 
-func compareToBaseline(testPkg, privateRepo string, run []byte) error {
-	var buf bytes.Buffer
+// tooMuchWorse is a benchstat.Collection.DeltaTest implementation that hands
+// benchstat back the real U-test p-value, unmodified, so Collection's own
+// notion of "significant" (p < its Alpha) stays meaningful in the printed
+// tables. It does NOT encode the regressed/not-regressed decision in its
+// return value — a solution is only rejected when the slowdown is both
+// statistically significant (p < benchSignificanceAlpha) AND the student
+// mean exceeds multiplier times the baseline mean, and that decision is
+// made here, once, and recorded on the side via results; compareToBaseline
+// reads results[i].Status rather than re-deriving it from benchstat's Change
+// field, which only reflects the p-value half of the test.
+//
+// As a side effect it appends a report.BenchmarkResult per comparison to
+// results, in the same order benchstat invokes it; compareToBaseline zips
+// that list back up with the row names from the resulting tables.
+func tooMuchWorse(multiplier float64, results *[]report.BenchmarkResult) func(old, new *benchstat.Metrics) (float64, error) {
+	return func(old, new *benchstat.Metrics) (float64, error) {
+		pValue, err := benchstat.UTest(old, new)
+
+		regressed := err == nil && pValue < benchSignificanceAlpha && new.Mean > multiplier*old.Mean
+
+		status := report.StatusPass
+		if regressed {
+			status = report.StatusFail
+		}
+		*results = append(*results, report.BenchmarkResult{
+			BaselineMean: old.Mean,
+			StudentMean:  new.Mean,
+			Unit:         old.Unit,
+			PValue:       pValue,
+			Multiplier:   multiplier,
+			Status:       status,
+		})
+		if err != nil {
+			return 0, err
+		}
+
+		return pValue, nil
+	}
+}
 
-	goTest := exec.Command("go", "test", "-tags", "private,solution", "-bench=.", "-run=^$", testPkg)
-	goTest.Dir = privateRepo
-	goTest.Stdout = &buf
-	goTest.Stderr = os.Stderr
-	if err := goTest.Run(); err != nil {
-		return fmt.Errorf("baseline benchmark failed: %w", err)
+// compareToBaseline runs the baseline solution's benchmarks benchReq.Count
+// times, compares them against the student's already-collected runs, and
+// returns one report.BenchmarkResult per benchmark gated.
+func compareToBaseline(testPkg, privateRepo string, studentRuns []byte, benchReq benchmarkRequirements) ([]report.BenchmarkResult, error) {
+	baselineRuns, err := repeatBenchmark(func() ([]byte, error) {
+		var buf bytes.Buffer
+
+		goTest := exec.Command("go", "test", "-tags", "private,solution", "-bench="+benchReq.benchRegexp(), "-run=^$", "-test.count=1", testPkg)
+		goTest.Dir = privateRepo
+		goTest.Stdout = &buf
+		goTest.Stderr = os.Stderr
+		err := goTest.Run()
+		return buf.Bytes(), err
+	}, benchReq.Count)
+	if err != nil {
+		return nil, fmt.Errorf("baseline benchmark failed: %w", err)
 	}
 
+	var results []report.BenchmarkResult
 	c := &benchstat.Collection{
-		DeltaTest: noMoreThanTwoTimesWorse,
+		Alpha:     benchSignificanceAlpha,
+		DeltaTest: tooMuchWorse(benchReq.Multiplier, &results),
 	}
-	c.AddConfig("baseline.txt", buf.Bytes())
-	c.AddConfig("new.txt", run)
+	c.AddConfig("baseline.txt", baselineRuns)
+	c.AddConfig("new.txt", studentRuns)
 
 	tables := c.Tables()
 	benchstat.FormatText(os.Stderr, tables)
 
+	// Name the results in row order; the regressed/not-regressed verdict
+	// itself was already decided in tooMuchWorse (p-value AND multiplier),
+	// not re-derived from benchstat's own Change field, which only reflects
+	// the p-value half of that test.
+	var i int
+	significant := false
 	for _, c := range tables {
 		for _, r := range c.Rows {
-			if r.Change == -1 {
-				return fmt.Errorf("solution is worse than baseline on benchmark %q", r.Benchmark)
+			if i >= len(results) {
+				continue
 			}
+			results[i].Name = r.Benchmark
+			if results[i].Status == report.StatusFail {
+				significant = true
+				log.Printf("solution is significantly worse than baseline on benchmark %q", r.Benchmark)
+			}
+			i++
 		}
 	}
 
-	return nil
+	if significant {
+		return results, fmt.Errorf("one or more benchmarks regressed beyond %.1fx the baseline (p < %.2f)", benchReq.Multiplier, benchSignificanceAlpha)
+	}
+
+	log.Printf("no significant benchmark change")
+	return results, nil
 }
 
 // relPaths converts paths to relative (to the baseDir) ones.
@@ -0,0 +1,74 @@
+package commands
+
+import (
+	"testing"
+
+	"golang.org/x/perf/benchstat"
+
+	"gitlab.com/slon/shad-go/tools/testtool/commands/report"
+)
+
+// metricsFromSamples builds a *benchstat.Metrics from raw measurements,
+// using the samples themselves as the outlier-removed RValues that
+// benchstat.UTest actually reads.
+func metricsFromSamples(t *testing.T, values []float64) *benchstat.Metrics {
+	t.Helper()
+
+	m := &benchstat.Metrics{Unit: "ns/op", Values: values, RValues: values}
+	m.Min, m.Max = values[0], values[0]
+	var sum float64
+	for _, v := range values {
+		sum += v
+		if v < m.Min {
+			m.Min = v
+		}
+		if v > m.Max {
+			m.Max = v
+		}
+	}
+	m.Mean = sum / float64(len(values))
+	return m
+}
+
+func TestTooMuchWorseFlagsSignificantRegressionBeyondMultiplier(t *testing.T) {
+	old := metricsFromSamples(t, []float64{100, 101, 99, 100, 100, 101})
+	new := metricsFromSamples(t, []float64{500, 501, 499, 500, 500, 501})
+
+	var results []report.BenchmarkResult
+	pValue, err := tooMuchWorse(2.0, &results)(old, new)
+	if err != nil {
+		t.Fatalf("tooMuchWorse: %s", err)
+	}
+	if pValue >= benchSignificanceAlpha {
+		t.Fatalf("expected a small p-value for a tight, clearly separated sample, got %v", pValue)
+	}
+	if len(results) != 1 || results[0].Status != report.StatusFail {
+		t.Fatalf("expected a flagged regression, got %+v", results)
+	}
+}
+
+func TestTooMuchWorsePassesNoisySamplesWithSmallDelta(t *testing.T) {
+	old := metricsFromSamples(t, []float64{100, 140, 60, 120, 80, 110})
+	new := metricsFromSamples(t, []float64{101, 141, 61, 121, 81, 111})
+
+	var results []report.BenchmarkResult
+	if _, err := tooMuchWorse(2.0, &results)(old, new); err != nil {
+		t.Fatalf("tooMuchWorse: %s", err)
+	}
+	if len(results) != 1 || results[0].Status != report.StatusPass {
+		t.Fatalf("expected noisy near-identical samples to pass, got %+v", results)
+	}
+}
+
+func TestTooMuchWorsePassesSignificantDeltaWithinMultiplier(t *testing.T) {
+	old := metricsFromSamples(t, []float64{100, 101, 99, 100, 100, 101})
+	new := metricsFromSamples(t, []float64{150, 151, 149, 150, 150, 151})
+
+	var results []report.BenchmarkResult
+	if _, err := tooMuchWorse(2.0, &results)(old, new); err != nil {
+		t.Fatalf("tooMuchWorse: %s", err)
+	}
+	if len(results) != 1 || results[0].Status != report.StatusPass {
+		t.Fatalf("expected a significant-but-within-multiplier delta to pass, got %+v", results)
+	}
+}
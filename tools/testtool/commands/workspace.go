@@ -0,0 +1,192 @@
+package commands
+
+import (
+	"fmt"
+	"log"
+	"os"
+	"os/exec"
+	"path"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// helperModules are sibling modules under the private repo that a
+// per-problem go.mod may depend on locally, instead of through the shared
+// top-level module.
+var helperModules = []string{"testtool", "common"}
+
+// problemHasOwnModule reports whether privateRepo/problem declares its own
+// go.mod, opting that problem out of the shared top-level module so it can
+// pin dependencies (e.g. gonum, pgx) without affecting anyone else.
+func problemHasOwnModule(privateRepo, problem string) bool {
+	return fileExists(path.Join(privateRepo, problem, "go.mod"))
+}
+
+func fileExists(p string) bool {
+	_, err := os.Stat(p)
+	return err == nil
+}
+
+// setupModule arranges for tmpRepo to build under the right Go module: the
+// shared top-level one by default, copied as before, or the problem's own
+// go.mod plus a go.work tying in sibling helper modules (falling back to a
+// synthesized replace-based go.mod on toolchains that predate workspaces).
+func setupModule(privateRepo, problem, tmpRepo string) {
+	if !problemHasOwnModule(privateRepo, problem) {
+		copyFiles(privateRepo, []string{"go.mod", "go.sum", ".golangci.yml"}, tmpRepo)
+		return
+	}
+
+	log.Printf("problem %s pins its own go.mod", problem)
+	copyFiles(privateRepo, []string{".golangci.yml"}, tmpRepo)
+	copyFileFlat(path.Join(privateRepo, problem, "go.mod"), filepath.Join(tmpRepo, "go.mod"))
+	if sumPath := path.Join(privateRepo, problem, "go.sum"); fileExists(sumPath) {
+		copyFileFlat(sumPath, filepath.Join(tmpRepo, "go.sum"))
+	}
+
+	var helpers []string
+	for _, m := range helperModules {
+		if fileExists(path.Join(privateRepo, m, "go.mod")) {
+			copyDir(privateRepo, m, tmpRepo)
+			helpers = append(helpers, m)
+		}
+	}
+	if len(helpers) == 0 {
+		return
+	}
+
+	if goSupportsWorkspaces() {
+		writeGoWork(tmpRepo, helpers)
+	} else {
+		addReplaceDirectives(tmpRepo, helpers)
+	}
+}
+
+// copyFileFlat copies a single file, ignoring the directory structure of
+// src, unlike copyFiles/copyDir which preserve it relative to a base dir.
+func copyFileFlat(src, dst string) {
+	info, err := os.Stat(src)
+	if err != nil {
+		log.Fatalf("file copying failed: %s", err)
+	}
+
+	raw, err := os.ReadFile(src)
+	if err != nil {
+		log.Fatalf("file copying failed: %s", err)
+	}
+
+	if err := os.WriteFile(dst, raw, info.Mode()); err != nil {
+		log.Fatalf("file copying failed: %s", err)
+	}
+}
+
+// goSupportsWorkspaces reports whether the go binary on PATH understands
+// `go work`, available since Go 1.18.
+func goSupportsWorkspaces() bool {
+	out, err := exec.Command("go", "work", "edit", "-fmt").CombinedOutput()
+	if err == nil {
+		return true
+	}
+	// A workspace-capable toolchain without a go.work file still recognizes
+	// the subcommand and fails with "no go.work file found"; only older
+	// toolchains report the subcommand itself as unknown.
+	return !strings.Contains(string(out), "unknown command")
+}
+
+// writeGoWork ties tmpRepo's module (the problem's own go.mod, now at its
+// root) together with the given sibling helper modules.
+func writeGoWork(tmpRepo string, helpers []string) {
+	members := []string{filepath.Join(tmpRepo, "go.mod")}
+	for _, h := range helpers {
+		members = append(members, filepath.Join(tmpRepo, h, "go.mod"))
+	}
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "go %s\n\nuse (\n\t.\n", maxGoVersion(members))
+	for _, h := range helpers {
+		fmt.Fprintf(&b, "\t./%s\n", h)
+	}
+	b.WriteString(")\n")
+
+	if err := os.WriteFile(filepath.Join(tmpRepo, "go.work"), []byte(b.String()), 0644); err != nil {
+		log.Fatalf("writing go.work: %s", err)
+	}
+}
+
+var goDirectiveRe = regexp.MustCompile(`(?m)^go\s+(\d+\.\d+(?:\.\d+)?)\s*$`)
+
+// goModVersion extracts the `go` directive from a go.mod file, returning ""
+// if the file is missing or declares no directive.
+func goModVersion(modPath string) string {
+	raw, err := os.ReadFile(modPath)
+	if err != nil {
+		return ""
+	}
+	m := goDirectiveRe.FindSubmatch(raw)
+	if m == nil {
+		return ""
+	}
+	return string(m[1])
+}
+
+// maxGoVersion returns the highest `go` directive among the given go.mod
+// paths, defaulting to 1.18 (the version workspaces themselves require)
+// when none declare one. go.work requires its own go version to be >= every
+// member module's, so a fixed constant would fail for any member whose own
+// go.mod asks for a newer Go than that.
+func maxGoVersion(modPaths []string) string {
+	best := "1.18"
+	for _, p := range modPaths {
+		if v := goModVersion(p); v != "" && compareGoVersions(v, best) > 0 {
+			best = v
+		}
+	}
+	return best
+}
+
+// compareGoVersions compares dot-separated numeric version strings (e.g.
+// go.mod's bare "1.21"), returning a negative, zero, or positive number as a
+// is less than, equal to, or greater than b.
+func compareGoVersions(a, b string) int {
+	as, bs := strings.Split(a, "."), strings.Split(b, ".")
+	for i := 0; i < len(as) || i < len(bs); i++ {
+		var av, bv int
+		if i < len(as) {
+			av, _ = strconv.Atoi(as[i])
+		}
+		if i < len(bs) {
+			bv, _ = strconv.Atoi(bs[i])
+		}
+		if av != bv {
+			return av - bv
+		}
+	}
+	return 0
+}
+
+// addReplaceDirectives is the fallback for toolchains older than Go 1.18:
+// it points the problem's go.mod at the locally copied helper modules
+// directly, the same effect a go.work achieves via a member list.
+func addReplaceDirectives(tmpRepo string, helpers []string) {
+	modPath := filepath.Join(tmpRepo, "go.mod")
+
+	raw, err := os.ReadFile(modPath)
+	if err != nil {
+		log.Fatalf("reading %s: %s", modPath, err)
+	}
+
+	b := strings.Builder{}
+	b.Write(raw)
+	if len(raw) > 0 && raw[len(raw)-1] != '\n' {
+		b.WriteString("\n")
+	}
+	for _, h := range helpers {
+		fmt.Fprintf(&b, "\nreplace %s => ./%s\n", path.Join(moduleImportPath, h), h)
+	}
+
+	if err := os.WriteFile(modPath, []byte(b.String()), 0644); err != nil {
+		log.Fatalf("writing %s: %s", modPath, err)
+	}
+}